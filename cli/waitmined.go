@@ -0,0 +1,79 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// minWaitMinedBackoff and maxWaitMinedBackoff bound the exponential backoff
+// used by WaitMined between polls of the transaction's receipt.
+const (
+	minWaitMinedBackoff = time.Second
+	maxWaitMinedBackoff = 30 * time.Second
+)
+
+// ErrDroppedFromMempool is returned by WaitMined when the transaction being
+// waited on is no longer known to the node, having been dropped from its
+// mempool before being mined.
+var ErrDroppedFromMempool = errors.New("transaction dropped from mempool")
+
+// WaitMinedBackend is the subset of *ethclient.Client that WaitMined needs.
+// It is satisfied by *ethclient.Client itself, and allows tests to exercise
+// WaitMined's backoff and dropped-transaction detection against a fake
+// implementation instead of a live or mocked RPC server.
+type WaitMinedBackend interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error)
+}
+
+// WaitMined blocks until tx is mined, the context is cancelled, or tx is
+// found to have been dropped from the node's mempool.  It mirrors the
+// polling pattern of go-ethereum's accounts/abi/bind.WaitMined, waiting
+// between polls for a delay that backs off exponentially from 1s up to a cap
+// of 30s so that long waits do not hammer the node.
+func WaitMined(ctx context.Context, client WaitMinedBackend, tx *types.Transaction) (*types.Receipt, error) {
+	txHash := tx.Hash()
+	backoff := minWaitMinedBackoff
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+
+		if _, _, txErr := client.TransactionByHash(ctx, txHash); txErr != nil && errors.Is(txErr, ethereum.NotFound) {
+			return nil, ErrDroppedFromMempool
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxWaitMinedBackoff {
+			backoff = maxWaitMinedBackoff
+		}
+	}
+}
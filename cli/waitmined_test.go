@@ -0,0 +1,85 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeWaitMinedBackend is a minimal WaitMinedBackend that never talks to a
+// node, allowing WaitMined's branching to be exercised directly.
+type fakeWaitMinedBackend struct {
+	receipt *types.Receipt
+	known   bool
+}
+
+func (f *fakeWaitMinedBackend) TransactionReceipt(_ context.Context, _ common.Hash) (*types.Receipt, error) {
+	if f.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return f.receipt, nil
+}
+
+func (f *fakeWaitMinedBackend) TransactionByHash(_ context.Context, _ common.Hash) (*types.Transaction, bool, error) {
+	if !f.known {
+		return nil, false, ethereum.NotFound
+	}
+	return types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil), true, nil
+}
+
+func testTx() *types.Transaction {
+	return types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+}
+
+func TestWaitMinedReturnsReceipt(t *testing.T) {
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful}
+	backend := &fakeWaitMinedBackend{receipt: receipt}
+
+	got, err := WaitMined(context.Background(), backend, testTx())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != receipt {
+		t.Fatalf("expected receipt %v, got %v", receipt, got)
+	}
+}
+
+func TestWaitMinedReturnsErrDroppedFromMempool(t *testing.T) {
+	backend := &fakeWaitMinedBackend{known: false}
+
+	_, err := WaitMined(context.Background(), backend, testTx())
+	if !errors.Is(err, ErrDroppedFromMempool) {
+		t.Fatalf("expected ErrDroppedFromMempool, got %v", err)
+	}
+}
+
+func TestWaitMinedRespectsContextCancellation(t *testing.T) {
+	backend := &fakeWaitMinedBackend{known: true}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := WaitMined(ctx, backend, testTx())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/wealdtech/ethereal/v2/cli"
+)
+
+// exitTimeout is returned by the commands that submit a replacement
+// transaction (cancel, speedup, resend) when it is sent successfully but not
+// mined within the time allowed by --wait.
+const exitTimeout = 2
+
+// handleSubmittedTransaction reports a transaction that has just been sent
+// to the network and, if --wait is set, blocks until it is mined using
+// cli.WaitMined before deciding the process' exit status.  report controls
+// whether the transaction ID is printed; callers that have already printed
+// it pass false.  It always exits the process, so a command that submits
+// several transactions calls it only for the last of them.
+func handleSubmittedTransaction(tx *types.Transaction, fields log.Fields, report bool) {
+	log.WithFields(fields).Info(tx.Hash().Hex())
+	if report && !quiet {
+		fmt.Printf("Transaction ID is %s\n", tx.Hash().Hex())
+	}
+
+	if !wait {
+		os.Exit(exitSuccess)
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	receipt, err := cli.WaitMined(ctx, client, tx)
+	if errors.Is(err, cli.ErrDroppedFromMempool) || errors.Is(err, context.DeadlineExceeded) {
+		if !quiet {
+			fmt.Printf("Transaction %s not mined within the time allowed\n", tx.Hash().Hex())
+		}
+		os.Exit(exitTimeout)
+	}
+	cli.ErrCheck(err, quiet, "Failed to wait for transaction to be mined")
+
+	if !quiet {
+		fmt.Printf("Transaction %s mined in block %d\n", tx.Hash().Hex(), receipt.BlockNumber.Uint64())
+	}
+	os.Exit(exitSuccess)
+}
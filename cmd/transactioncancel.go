@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -30,6 +31,9 @@ import (
 
 var transactionCancelAmount string
 var transactionCancelToAddress string
+var transactionCancelMaxFeePerGas string
+var transactionCancelMaxPriorityFeePerGas string
+var transactionCancelTipBumpPercent int64
 
 // transactionCancelCmd represents the transaction up command
 var transactionCancelCmd = &cobra.Command{
@@ -41,6 +45,8 @@ var transactionCancelCmd = &cobra.Command{
 
 Note that Ethereum does not have the ability to cancel a pending transaction, so this overwrites the pending transaction with a 0-value transfer back to the address sender.  It will, however, still need to be mined so choose an appropriate gas price.  If not supplied then the gas price will default to just over 10% higher than the gas price of the transaction to be cancelled.
 
+If the pending transaction is an EIP-1559 transaction then the replacement is also an EIP-1559 transaction, with --max-fee-per-gas and --max-priority-fee-per-gas each bumped by --tip-bump-percent (default 10%) over the original.  If neither flag is supplied the bumped values are compared against an eth_feeHistory-based suggestion and the higher of the two is used.
+
 The cancellation transaction will cost 21000 gas.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
@@ -53,13 +59,23 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
 		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
 
-		minGasPrice := new(big.Int).Add(new(big.Int).Add(tx.GasPrice(), new(big.Int).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(1))
-		if viper.GetString("gasprice") == "" {
-			// No gas price supplied; use the calculated minimum
-			gasPrice = minGasPrice
+		if tx.Type() == types.DynamicFeeTxType {
+			// The pending transaction is itself an EIP-1559 transaction, so the
+			// replacement must also be one, with both the fee cap and tip cap
+			// bumped by at least the geth-required percentage.
+			var err error
+			maxFeePerGas, maxPriorityFeePerGas, err = replacementDynamicFee(ctx, tx, transactionCancelMaxFeePerGas, transactionCancelMaxPriorityFeePerGas, transactionCancelTipBumpPercent)
+			cli.ErrCheck(err, quiet, "Failed to calculate replacement fees")
+			gasPrice = nil
 		} else {
-			// Gas price supplied; ensure it is over 10% more than the current gas price
-			cli.Assert(gasPrice.Cmp(minGasPrice) > 0, quiet, fmt.Sprintf("Gas price must be at least %s", string2eth.WeiToString(minGasPrice, true)))
+			minGasPrice := new(big.Int).Add(new(big.Int).Add(tx.GasPrice(), new(big.Int).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(1))
+			if viper.GetString("gasprice") == "" {
+				// No gas price supplied; use the calculated minimum
+				gasPrice = minGasPrice
+			} else {
+				// Gas price supplied; ensure it is over 10% more than the current gas price
+				cli.Assert(gasPrice.Cmp(minGasPrice) > 0, quiet, fmt.Sprintf("Gas price must be at least %s", string2eth.WeiToString(minGasPrice, true)))
+			}
 		}
 
 		// Create and sign the transaction
@@ -96,5 +112,8 @@ func init() {
 	transactionFlags(transactionCancelCmd)
 	transactionCancelCmd.Flags().StringVar(&transactionCancelAmount, "amount", "", "Amount of Ether to transfer")
 	transactionCancelCmd.Flags().StringVar(&transactionCancelToAddress, "to", "", "Address to which to transfer Ether")
+	transactionCancelCmd.Flags().StringVar(&transactionCancelMaxFeePerGas, "max-fee-per-gas", "", "Maximum fee per gas for the replacement transaction, if it is an EIP-1559 transaction")
+	transactionCancelCmd.Flags().StringVar(&transactionCancelMaxPriorityFeePerGas, "max-priority-fee-per-gas", "", "Maximum priority fee per gas for the replacement transaction, if it is an EIP-1559 transaction")
+	transactionCancelCmd.Flags().Int64Var(&transactionCancelTipBumpPercent, "tip-bump-percent", defaultTipBumpPercent, "Minimum percentage by which to bump the fee cap and tip cap of an EIP-1559 replacement transaction")
 	addTransactionFlags(transactionCancelCmd, "the address that holds the funds")
 }
@@ -0,0 +1,219 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/v2/cli"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var transactionCancelAllDryRun bool
+
+// cancelAllResult is a single row of the cancel-all summary table.
+type cancelAllResult struct {
+	nonce    uint64
+	oldHash  common.Hash
+	newHash  common.Hash
+	oldFee   string
+	newFee   string
+	status   string
+	signedTx *types.Transaction
+}
+
+// transactionCancelAllCmd represents the transaction cancel-all command
+var transactionCancelAllCmd = &cobra.Command{
+	Use:   "cancel-all",
+	Short: "Cancel every pending transaction for an address",
+	Long: `Cancel every pending transaction for an address.  For example:
+
+    ethereal transaction cancel-all --from=0x5FfC014343cd971B7eb70732021E26C35B744cc
+
+This enumerates every pending transaction for --from (via txpool_content, falling back to scanning by nonce from the pending count down to the mined count if the node does not support it) and overwrites each with a nonce-preserving, 0-value self-transfer at a bumped fee, in the same manner as a single "transaction cancel".
+
+Use --dry-run to see what would be replaced without submitting anything, and --wait to block until every replacement has been mined.
+
+This will return an exit status of 0 if every replacement is successfully submitted (and mined if --wait is supplied), or 1 if any replacement is not successfully submitted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(from != (common.Address{}), quiet, "--from is required")
+		ctx, cancel := localContext()
+		defer cancel()
+
+		entries, err := pendingEntriesForAddress(ctx, from)
+		cli.ErrCheck(err, quiet, "Failed to obtain pending transactions")
+		cli.Assert(len(entries) > 0, quiet, fmt.Sprintf("No pending transactions for %s", from.Hex()))
+
+		results := make([]*cancelAllResult, 0, len(entries))
+		for _, entry := range entries {
+			results = append(results, cancelPoolEntry(ctx, entry))
+		}
+
+		if wait {
+			for _, result := range results {
+				if result.status != "submitted" {
+					continue
+				}
+				waitCtx, waitCancel := localContext()
+				receipt, err := cli.WaitMined(waitCtx, client, result.signedTx)
+				waitCancel()
+				switch {
+				case err == nil && receipt != nil:
+					result.status = "mined"
+				case errors.Is(err, cli.ErrDroppedFromMempool):
+					result.status = "dropped"
+				default:
+					result.status = "timeout"
+				}
+			}
+		}
+
+		printCancelAllSummary(results)
+
+		for _, result := range results {
+			switch result.status {
+			case "failed":
+				os.Exit(exitFailure)
+			case "timeout", "dropped":
+				// Only reachable when --wait was supplied, since these statuses
+				// are only ever set by the wait loop above.
+				os.Exit(exitFailure)
+			}
+		}
+		os.Exit(exitSuccess)
+	},
+}
+
+// pendingEntriesForAddress enumerates pending transactions via txpool_content,
+// falling back to scanning the nonce range between the mined and pending
+// nonce if the node does not support txpool_content.
+func pendingEntriesForAddress(ctx context.Context, address common.Address) ([]*txPoolEntry, error) {
+	entries, err := pendingTxsForAddress(ctx, address)
+	if err == nil && len(entries) > 0 {
+		return entries, nil
+	}
+
+	latestNonce, err := client.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain mined nonce: %v", err)
+	}
+	pendingNonce, err := client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain pending nonce: %v", err)
+	}
+
+	entries = make([]*txPoolEntry, 0, pendingNonce-latestNonce)
+	for n := latestNonce; n < pendingNonce; n++ {
+		entries = append(entries, &txPoolEntry{Nonce: n})
+	}
+	return entries, nil
+}
+
+// cancelPoolEntry computes the minimum replacement fee for a single pending
+// transaction and, unless --dry-run is set, submits the cancellation.
+func cancelPoolEntry(ctx context.Context, entry *txPoolEntry) *cancelAllResult {
+	result := &cancelAllResult{nonce: entry.Nonce, oldHash: entry.Hash}
+
+	switch {
+	case entry.MaxFeePerGas != nil:
+		// txpool_content always populates gasPrice (as the effective price)
+		// even for EIP-1559 transactions, so the dynamic-fee case must be
+		// checked before the legacy one.
+		result.oldFee = string2eth.WeiToString(entry.MaxFeePerGas.ToInt(), true)
+		maxFeePerGas = bumpByPercent(entry.MaxFeePerGas.ToInt(), big.NewInt(defaultTipBumpPercent))
+		maxPriorityFeePerGas = bumpByPercent(entry.MaxPriorityFeePerGas.ToInt(), big.NewInt(defaultTipBumpPercent))
+		gasPrice = nil
+	case entry.GasPrice != nil:
+		result.oldFee = string2eth.WeiToString(entry.GasPrice.ToInt(), true)
+		gasPrice = bumpByPercent(entry.GasPrice.ToInt(), big.NewInt(defaultTipBumpPercent))
+		maxFeePerGas = nil
+		maxPriorityFeePerGas = nil
+	default:
+		// The entry came from the nonce-scanning fallback, so the original
+		// fee is unknown; use the network's current suggestion instead.
+		result.oldFee = "unknown"
+		suggested, err := client.SuggestGasPrice(ctx)
+		cli.ErrCheck(err, quiet, "Failed to obtain suggested gas price")
+		gasPrice = suggested
+		maxFeePerGas = nil
+		maxPriorityFeePerGas = nil
+	}
+	if gasPrice != nil {
+		result.newFee = string2eth.WeiToString(gasPrice, true)
+	} else {
+		result.newFee = string2eth.WeiToString(maxFeePerGas, true)
+	}
+
+	if transactionCancelAllDryRun {
+		result.status = "would replace"
+		return result
+	}
+
+	nonce = int64(entry.Nonce)
+	signedTx, err := createSignedTransaction(from, &from, nil, gasLimit, nil)
+	if err != nil {
+		result.status = "failed"
+		return result
+	}
+	result.newHash = signedTx.Hash()
+	result.signedTx = signedTx
+
+	sendCtx, cancel := localContext()
+	defer cancel()
+	if err := client.SendTransaction(sendCtx, signedTx); err != nil {
+		result.status = "failed"
+		return result
+	}
+	result.status = "submitted"
+
+	log.WithFields(log.Fields{
+		"group":            "transaction",
+		"command":          "cancel-all",
+		"oldtransactionid": entry.Hash.Hex(),
+	}).Info(signedTx.Hash().Hex())
+
+	return result
+}
+
+func printCancelAllSummary(results []*cancelAllResult) {
+	if quiet {
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Nonce\tOld hash\tNew hash\tOld fee\tNew fee\tStatus")
+	for _, result := range results {
+		newHash := ""
+		if result.newHash != (common.Hash{}) {
+			newHash = result.newHash.Hex()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", result.nonce, result.oldHash.Hex(), newHash, result.oldFee, result.newFee, result.status)
+	}
+	w.Flush()
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionCancelAllCmd)
+	transactionFlags(transactionCancelAllCmd)
+	transactionCancelAllCmd.Flags().BoolVar(&transactionCancelAllDryRun, "dry-run", false, "Print what would be replaced without submitting any transactions")
+	addTransactionFlags(transactionCancelAllCmd, "the address that holds the funds")
+}
@@ -0,0 +1,90 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// txPoolEntry is a single pending transaction as reported by txpool_content.
+type txPoolEntry struct {
+	Hash                 common.Hash
+	Nonce                uint64
+	To                   *common.Address
+	Value                *hexutil.Big
+	Gas                  hexutil.Uint64
+	GasPrice             *hexutil.Big
+	MaxFeePerGas         *hexutil.Big
+	MaxPriorityFeePerGas *hexutil.Big
+	Data                 hexutil.Bytes
+}
+
+// txPoolRawTx mirrors the per-transaction JSON returned by the txpool_content
+// RPC call, ready to be decoded with encoding/json.
+type txPoolRawTx struct {
+	Hash                 common.Hash     `json:"hash"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	To                   *common.Address `json:"to"`
+	Value                *hexutil.Big    `json:"value"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Input                hexutil.Bytes   `json:"input"`
+}
+
+// txPoolContent mirrors the result of the txpool_content RPC call: a map of
+// sender address to a map of nonce (as a decimal string) to transaction.
+type txPoolContent struct {
+	Pending map[common.Address]map[string]txPoolRawTx `json:"pending"`
+	Queued  map[common.Address]map[string]txPoolRawTx `json:"queued"`
+}
+
+// pendingTxsForAddress fetches every pending (as opposed to queued)
+// transaction in the node's mempool for the given address, via
+// txpool_content, ordered by ascending nonce.
+func pendingTxsForAddress(ctx context.Context, address common.Address) ([]*txPoolEntry, error) {
+	var content txPoolContent
+	if err := rpcClient.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, fmt.Errorf("failed to obtain transaction pool content: %v", err)
+	}
+
+	txs, exists := content.Pending[address]
+	if !exists || len(txs) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]*txPoolEntry, 0, len(txs))
+	for _, tx := range txs {
+		entries = append(entries, &txPoolEntry{
+			Hash:                 tx.Hash,
+			Nonce:                uint64(tx.Nonce),
+			To:                   tx.To,
+			Value:                tx.Value,
+			Gas:                  tx.Gas,
+			GasPrice:             tx.GasPrice,
+			MaxFeePerGas:         tx.MaxFeePerGas,
+			MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+			Data:                 tx.Input,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Nonce < entries[j].Nonce })
+
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+// defaultTipBumpPercent is the minimum bump geth's txpool requires for a
+// replacement transaction to be accepted in place of one already pending.
+const defaultTipBumpPercent = 10
+
+// bumpedFees calculates the minimum maxFeePerGas and maxPriorityFeePerGas
+// required to replace the given pending EIP-1559 transaction, bumping both
+// the original fee cap and tip cap by at least bumpPercent.
+func bumpedFees(tx *types.Transaction, bumpPercent int64) (*big.Int, *big.Int) {
+	bump := big.NewInt(bumpPercent)
+	return bumpByPercent(tx.GasFeeCap(), bump), bumpByPercent(tx.GasTipCap(), bump)
+}
+
+// bumpByPercent increases value by at least percent%, rounding up so that the
+// result is always strictly greater than a simple percentage increase.
+func bumpByPercent(value *big.Int, percent *big.Int) *big.Int {
+	bumped := new(big.Int).Add(value, new(big.Int).Div(new(big.Int).Mul(value, percent), big.NewInt(100)))
+	return new(big.Int).Add(bumped, big.NewInt(1))
+}
+
+// suggestedFees falls back to eth_feeHistory when the user has not supplied
+// explicit replacement fees, suggesting a maxFeePerGas/maxPriorityFeePerGas
+// pair based on recent network conditions.
+func suggestedFees(ctx context.Context) (*big.Int, *big.Int, error) {
+	feeHistory, err := client.FeeHistory(ctx, 1, nil, []float64{50})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(feeHistory.Reward) == 0 || len(feeHistory.Reward[0]) == 0 || len(feeHistory.BaseFee) == 0 {
+		return nil, nil, fmt.Errorf("no fee history available")
+	}
+	tipCap := feeHistory.Reward[0][0]
+	baseFee := feeHistory.BaseFee[len(feeHistory.BaseFee)-1]
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	return feeCap, tipCap, nil
+}
+
+// replacementDynamicFee works out the maxFeePerGas and maxPriorityFeePerGas
+// to use for a replacement of the given pending EIP-1559 transaction.  It
+// honours explicit user-supplied values (as long as they meet the minimum
+// bump), and otherwise falls back to an eth_feeHistory suggestion that is
+// still at least the minimum bump above the original transaction.
+func replacementDynamicFee(ctx context.Context,
+	tx *types.Transaction,
+	maxFeePerGasStr string,
+	maxPriorityFeePerGasStr string,
+	tipBumpPercent int64,
+) (*big.Int, *big.Int, error) {
+	minFeeCap, minTipCap := bumpedFees(tx, tipBumpPercent)
+
+	maxFeePerGas := minFeeCap
+	if maxFeePerGasStr != "" {
+		val, err := string2eth.StringToWei(maxFeePerGasStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --max-fee-per-gas: %v", err)
+		}
+		if val.Cmp(minFeeCap) < 0 {
+			return nil, nil, fmt.Errorf("max fee per gas must be at least %s", string2eth.WeiToString(minFeeCap, true))
+		}
+		maxFeePerGas = val
+	}
+
+	maxPriorityFeePerGas := minTipCap
+	if maxPriorityFeePerGasStr != "" {
+		val, err := string2eth.StringToWei(maxPriorityFeePerGasStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --max-priority-fee-per-gas: %v", err)
+		}
+		if val.Cmp(minTipCap) < 0 {
+			return nil, nil, fmt.Errorf("max priority fee per gas must be at least %s", string2eth.WeiToString(minTipCap, true))
+		}
+		maxPriorityFeePerGas = val
+	}
+
+	if maxFeePerGasStr == "" && maxPriorityFeePerGasStr == "" {
+		if suggestedFeeCap, suggestedTipCap, err := suggestedFees(ctx); err == nil {
+			if suggestedFeeCap.Cmp(maxFeePerGas) > 0 {
+				maxFeePerGas = suggestedFeeCap
+			}
+			if suggestedTipCap.Cmp(maxPriorityFeePerGas) > 0 {
+				maxPriorityFeePerGas = suggestedTipCap
+			}
+		}
+	}
+
+	return maxFeePerGas, maxPriorityFeePerGas, nil
+}
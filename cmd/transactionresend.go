@@ -0,0 +1,189 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/v2/cli"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var transactionResendAllPending bool
+var transactionResendMaxFeePerGas string
+var transactionResendMaxPriorityFeePerGas string
+
+// transactionResendCmd represents the transaction resend command
+var transactionResendCmd = &cobra.Command{
+	Use:   "resend",
+	Short: "Resend a stuck pending transaction",
+	Long: `Resend a stuck pending transaction.  For example:
+
+    ethereal transaction resend --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
+
+Unlike cancel and speedup, resend re-signs and re-broadcasts the transaction completely unchanged unless one of --gasprice, --max-fee-per-gas, --max-priority-fee-per-gas, --gaslimit or --data is supplied to override it.  The destination and value are never altered, so this is useful to push a transaction back out to the network when it has fallen out of most nodes' mempools without affecting its original intent.
+
+With --all-pending and --from, every pending transaction for the given address is resent in turn, each bumped by the standard 10% fee increase so that nodes that still hold the original will accept the replacement.
+
+This will return an exit status of 0 if the transaction(s) are successfully submitted (and mined if --wait is supplied), 1 if a transaction is not successfully submitted, and 2 if a transaction is successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := localContext()
+		defer cancel()
+
+		if transactionResendAllPending {
+			cli.Assert(from != (common.Address{}), quiet, "--from is required with --all-pending")
+			entries, err := pendingTxsForAddress(ctx, from)
+			cli.ErrCheck(err, quiet, "Failed to obtain pending transactions")
+			cli.Assert(len(entries) > 0, quiet, fmt.Sprintf("No pending transactions for %s", from.Hex()))
+			for i, entry := range entries {
+				resendPoolEntry(ctx, entry, i == len(entries)-1)
+			}
+			os.Exit(exitSuccess)
+		}
+
+		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		txHash := common.HexToHash(transactionStr)
+		tx, pending, err := client.TransactionByHash(ctx, txHash)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
+		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
+
+		fromAddress, err := txFrom(tx)
+		cli.ErrCheck(err, quiet, "Failed to obtain from address")
+
+		resendData := tx.Data()
+		if viper.GetString("data") != "" {
+			resendData, err = hex.DecodeString(strings.TrimPrefix(viper.GetString("data"), "0x"))
+			cli.ErrCheck(err, quiet, "Invalid --data")
+		}
+
+		resendGasLimit := tx.Gas()
+		if cmd.Flags().Changed("gaslimit") {
+			resendGasLimit = gasLimit
+		}
+
+		applyResendFees(cmd, tx, transactionResendMaxFeePerGas, transactionResendMaxPriorityFeePerGas)
+
+		nonce = int64(tx.Nonce())
+		signedTx, err := createSignedTransaction(fromAddress, tx.To(), tx.Value(), resendGasLimit, resendData)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		submitResentTransaction(signedTx, txHash, true)
+	},
+}
+
+// applyResendFees sets the package-level fee globals used by
+// createSignedTransaction, preferring any user-supplied overrides (read from
+// the shared --gasprice flag registered by transactionFlags, or the
+// resend-specific 1559 flags) and otherwise leaving the original
+// transaction's fees unchanged.
+func applyResendFees(cmd *cobra.Command, tx *types.Transaction, maxFeePerGasStr string, maxPriorityFeePerGasStr string) {
+	if tx.Type() == types.DynamicFeeTxType {
+		maxFeePerGas = tx.GasFeeCap()
+		maxPriorityFeePerGas = tx.GasTipCap()
+		if maxFeePerGasStr != "" {
+			maxFeePerGas = mustParseWei(maxFeePerGasStr, "--max-fee-per-gas")
+		}
+		if maxPriorityFeePerGasStr != "" {
+			maxPriorityFeePerGas = mustParseWei(maxPriorityFeePerGasStr, "--max-priority-fee-per-gas")
+		}
+		gasPrice = nil
+	} else if !cmd.Flags().Changed("gasprice") {
+		gasPrice = tx.GasPrice()
+	}
+}
+
+// resendPoolEntry resends a single pending transaction pulled from the
+// mempool, bumping its fee by the standard minimum so that it replaces the
+// copy other nodes are still holding.
+func resendPoolEntry(ctx context.Context, entry *txPoolEntry, last bool) {
+	if entry.MaxFeePerGas != nil {
+		maxFeePerGas = bumpByPercent(entry.MaxFeePerGas.ToInt(), big.NewInt(defaultTipBumpPercent))
+		maxPriorityFeePerGas = bumpByPercent(entry.MaxPriorityFeePerGas.ToInt(), big.NewInt(defaultTipBumpPercent))
+		gasPrice = nil
+	} else {
+		maxFeePerGas = nil
+		maxPriorityFeePerGas = nil
+		gasPrice = bumpByPercent(entry.GasPrice.ToInt(), big.NewInt(defaultTipBumpPercent))
+	}
+
+	nonce = int64(entry.Nonce)
+	value := big.NewInt(0)
+	if entry.Value != nil {
+		value = entry.Value.ToInt()
+	}
+	signedTx, err := createSignedTransaction(from, entry.To, value, uint64(entry.Gas), entry.Data)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to create replacement for transaction %s", entry.Hash.Hex()))
+
+	submitResentTransaction(signedTx, entry.Hash, last)
+}
+
+// submitResentTransaction sends signedTx.  handleSubmittedTransaction exits
+// the process, so it is only called for the last transaction sent; earlier
+// ones are reported here and control returns to the caller.  Pending
+// transactions are resent in ascending nonce order, so waiting for the last
+// one to be mined also covers the ones before it.
+func submitResentTransaction(signedTx *types.Transaction, oldTxHash common.Hash, last bool) {
+	if offline {
+		if !quiet {
+			buf := new(bytes.Buffer)
+			cli.ErrCheck(signedTx.EncodeRLP(buf), quiet, "failed to encode transaction")
+			fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+		}
+		return
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	err := client.SendTransaction(ctx, signedTx)
+	cli.ErrCheck(err, quiet, "Failed to send transaction")
+	fields := log.Fields{
+		"group":            "transaction",
+		"command":          "resend",
+		"oldtransactionid": oldTxHash.Hex(),
+	}
+	if !last {
+		log.WithFields(fields).Info(signedTx.Hash().Hex())
+		if !quiet {
+			fmt.Printf("Transaction ID is %s\n", signedTx.Hash().Hex())
+		}
+		return
+	}
+	handleSubmittedTransaction(signedTx, fields, true)
+}
+
+func mustParseWei(str string, flag string) *big.Int {
+	val, err := string2eth.StringToWei(str)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid %s", flag))
+	return val
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionResendCmd)
+	transactionFlags(transactionResendCmd)
+	transactionResendCmd.Flags().BoolVar(&transactionResendAllPending, "all-pending", false, "Resend every pending transaction for --from")
+	transactionResendCmd.Flags().StringVar(&transactionResendMaxFeePerGas, "max-fee-per-gas", "", "Maximum fee per gas for the resent transaction, if it is an EIP-1559 transaction")
+	transactionResendCmd.Flags().StringVar(&transactionResendMaxPriorityFeePerGas, "max-priority-fee-per-gas", "", "Maximum priority fee per gas for the resent transaction, if it is an EIP-1559 transaction")
+	addTransactionFlags(transactionResendCmd, "the address that holds the funds")
+}
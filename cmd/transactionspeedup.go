@@ -0,0 +1,113 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/v2/cli"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var transactionSpeedupMaxFeePerGas string
+var transactionSpeedupMaxPriorityFeePerGas string
+var transactionSpeedupTipBumpPercent int64
+
+// transactionSpeedupCmd represents the transaction speedup command
+var transactionSpeedupCmd = &cobra.Command{
+	Use:   "speedup",
+	Short: "Speed up a pending transaction",
+	Long: `Speed up a pending transaction.  For example:
+
+    ethereal transaction speedup --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
+
+Unlike cancel, this resubmits the pending transaction with its original destination, value and data unchanged, only bumping its fee so that it is more likely to be picked up by miners.  If not supplied the gas price will default to just over 10% higher than the gas price of the transaction being replaced.
+
+If the pending transaction is an EIP-1559 transaction then the replacement is also an EIP-1559 transaction, with --max-fee-per-gas and --max-priority-fee-per-gas each bumped by --tip-bump-percent (default 10%) over the original.  If neither flag is supplied the bumped values are compared against an eth_feeHistory-based suggestion and the higher of the two is used.
+
+This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		txHash := common.HexToHash(transactionStr)
+		ctx, cancel := localContext()
+		defer cancel()
+		tx, pending, err := client.TransactionByHash(ctx, txHash)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
+		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
+
+		if tx.Type() == types.DynamicFeeTxType {
+			// The pending transaction is itself an EIP-1559 transaction, so the
+			// replacement must also be one, with both the fee cap and tip cap
+			// bumped by at least the geth-required percentage.
+			var err error
+			maxFeePerGas, maxPriorityFeePerGas, err = replacementDynamicFee(ctx, tx, transactionSpeedupMaxFeePerGas, transactionSpeedupMaxPriorityFeePerGas, transactionSpeedupTipBumpPercent)
+			cli.ErrCheck(err, quiet, "Failed to calculate replacement fees")
+			gasPrice = nil
+		} else {
+			minGasPrice := new(big.Int).Add(new(big.Int).Add(tx.GasPrice(), new(big.Int).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(1))
+			if viper.GetString("gasprice") == "" {
+				// No gas price supplied; use the calculated minimum
+				gasPrice = minGasPrice
+			} else {
+				// Gas price supplied; ensure it is over 10% more than the current gas price
+				cli.Assert(gasPrice.Cmp(minGasPrice) > 0, quiet, fmt.Sprintf("Gas price must be at least %s", string2eth.WeiToString(minGasPrice, true)))
+			}
+		}
+
+		// Create and sign the transaction, preserving the original destination, value and data
+		fromAddress, err := txFrom(tx)
+		cli.ErrCheck(err, quiet, "Failed to obtain from address")
+
+		nonce = int64(tx.Nonce())
+		signedTx, err := createSignedTransaction(fromAddress, tx.To(), tx.Value(), tx.Gas(), tx.Data())
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				buf := new(bytes.Buffer)
+				cli.ErrCheck(signedTx.EncodeRLP(buf), quiet, "failed to encode transaction")
+				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+			}
+			os.Exit(exitSuccess)
+		}
+
+		ctx, cancel = localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+		handleSubmittedTransaction(signedTx, log.Fields{
+			"group":            "transaction",
+			"command":          "speedup",
+			"oldtransactionid": txHash.Hex(),
+		}, true)
+	},
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionSpeedupCmd)
+	transactionFlags(transactionSpeedupCmd)
+	transactionSpeedupCmd.Flags().StringVar(&transactionSpeedupMaxFeePerGas, "max-fee-per-gas", "", "Maximum fee per gas for the replacement transaction, if it is an EIP-1559 transaction")
+	transactionSpeedupCmd.Flags().StringVar(&transactionSpeedupMaxPriorityFeePerGas, "max-priority-fee-per-gas", "", "Maximum priority fee per gas for the replacement transaction, if it is an EIP-1559 transaction")
+	transactionSpeedupCmd.Flags().Int64Var(&transactionSpeedupTipBumpPercent, "tip-bump-percent", defaultTipBumpPercent, "Minimum percentage by which to bump the fee cap and tip cap of an EIP-1559 replacement transaction")
+	addTransactionFlags(transactionSpeedupCmd, "the address that holds the funds")
+}
@@ -0,0 +1,102 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/v2/cli"
+)
+
+// Exit codes of "transaction wait", so that scripts can tell a timeout, a
+// dropped transaction and a reverted transaction apart.  The timeout code is
+// the same 2 that the other commands return on timeout; 3 and 4 are specific
+// to this command.
+const (
+	exitTransactionTimeout  = 2
+	exitTransactionDropped  = 3
+	exitTransactionReverted = 4
+)
+
+var transactionWaitTimeout time.Duration
+
+// transactionWaitCmd represents the transaction wait command
+var transactionWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for a transaction to be mined",
+	Long: `Wait for a transaction to be mined.  For example:
+
+    ethereal transaction wait --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c --timeout=10m
+
+This is useful after submitting an offline-signed transaction elsewhere, allowing a script to block until it is confirmed without resubmitting it.
+
+This will return an exit status of 0 if the transaction is mined, 2 if it is not mined within --timeout, 3 if it is dropped from the node's mempool before being mined, and 4 if it is mined but reverted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		txHash := common.HexToHash(transactionStr)
+
+		ctx, cancel := context.WithTimeout(context.Background(), transactionWaitTimeout)
+		defer cancel()
+
+		tx, _, err := client.TransactionByHash(ctx, txHash)
+		if errors.Is(err, ethereum.NotFound) {
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "Transaction %s not known to the node\n", txHash.Hex())
+			}
+			os.Exit(exitTransactionDropped)
+		}
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
+
+		receipt, err := cli.WaitMined(ctx, client, tx)
+		switch {
+		case errors.Is(err, cli.ErrDroppedFromMempool):
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "Transaction %s dropped from mempool\n", txHash.Hex())
+			}
+			os.Exit(exitTransactionDropped)
+		case errors.Is(err, context.DeadlineExceeded):
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "Timed out waiting for transaction %s to be mined\n", txHash.Hex())
+			}
+			os.Exit(exitTransactionTimeout)
+		case err != nil:
+			cli.ErrCheck(err, quiet, "Failed to wait for transaction")
+		}
+
+		if receipt.Status == 0 {
+			if !quiet {
+				fmt.Printf("Transaction %s mined in block %d but reverted\n", txHash.Hex(), receipt.BlockNumber.Uint64())
+			}
+			os.Exit(exitTransactionReverted)
+		}
+
+		if !quiet {
+			fmt.Printf("Transaction %s mined in block %d\n", txHash.Hex(), receipt.BlockNumber.Uint64())
+		}
+		os.Exit(exitSuccess)
+	},
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionWaitCmd)
+	transactionFlags(transactionWaitCmd)
+	transactionWaitCmd.Flags().DurationVar(&transactionWaitTimeout, "timeout", 5*time.Minute, "Maximum time to wait for the transaction to be mined")
+}